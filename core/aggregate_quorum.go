@@ -0,0 +1,86 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	errAggregateProofHeightMismatch = errors.New("aggregate quorum proof height does not match the current height")
+	errAggregateQuorumUnsupported   = errors.New("backend does not implement SignatureScheme; aggregate quorum is not supported")
+)
+
+// AggregateQuorumProof is a single aggregate/threshold signature over msgHash, together with a
+// bitmap identifying which validators (by sorted index) participated. It lets a commit carry one
+// signature instead of one per validator, while HasAggregateQuorum still enforces the same
+// voting-power quorum as the per-message HasQuorum path
+type AggregateQuorumProof struct {
+	// Bitmap has bit i set if the validator at sorted index i participated in Signature
+	Bitmap []byte
+
+	// Signature is the aggregate signature of all participating validators over msgHash
+	Signature []byte
+
+	// Height is the height the proof was produced for
+	Height uint64
+}
+
+// HasAggregateQuorum verifies proof against the validator set for the current height: it checks
+// that the participating validators (per proof.Bitmap) carry enough cumulative voting power for
+// quorum, then verifies proof.Signature as a valid aggregate signature over msgHash by exactly
+// those validators' public keys
+func (vm *ValidatorManager) HasAggregateQuorum(proof AggregateQuorumProof, msgHash []byte) (bool, error) {
+	scheme, ok := vm.backend.(SignatureScheme)
+	if !ok {
+		return false, errAggregateQuorumUnsupported
+	}
+
+	vm.vpLock.RLock()
+	defer vm.vpLock.RUnlock()
+
+	if vm.validatorsVotingPower == nil {
+		return false, nil
+	}
+
+	if proof.Height != vm.currentHeight {
+		return false, errAggregateProofHeightMismatch
+	}
+
+	participatingVotingPower := big.NewInt(0)
+	pubkeys := make([][]byte, 0)
+
+	for i, addr := range vm.sortedValidators {
+		if !bitmapIsSet(proof.Bitmap, i) {
+			continue
+		}
+
+		participatingVotingPower.Add(participatingVotingPower, vm.validatorsVotingPower[addr])
+
+		pubkey, err := scheme.PubKey(addr)
+		if err != nil {
+			return false, err
+		}
+
+		pubkeys = append(pubkeys, pubkey)
+	}
+
+	if participatingVotingPower.Cmp(vm.quorumSize) < 0 {
+		return false, nil
+	}
+
+	if err := scheme.VerifyAggregate(pubkeys, msgHash, proof.Signature); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// bitmapIsSet reports whether bit i is set in bitmap
+func bitmapIsSet(bitmap []byte, i int) bool {
+	byteIndex := i / 8
+	if byteIndex >= len(bitmap) {
+		return false
+	}
+
+	return bitmap[byteIndex]&(1<<(uint(i)%8)) != 0
+}
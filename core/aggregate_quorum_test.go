@@ -0,0 +1,123 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// TestHasAggregateQuorum_BitmapMapsToSortedValidators checks that bitmap bit i is resolved to the
+// validator at sorted index i (not map iteration order), and that quorum is based on the
+// cumulative voting power of exactly the participating validators
+func TestHasAggregateQuorum_BitmapMapsToSortedValidators(t *testing.T) {
+	// Sorted order: "a" < "b" < "c"
+	backend := NewInMemoryValidatorBackend("a", map[string]*big.Int{
+		"a": big.NewInt(1),
+		"b": big.NewInt(1),
+		"c": big.NewInt(1),
+	})
+	backend.SetPubKey("a", []byte("pubkey-a"))
+	backend.SetPubKey("b", []byte("pubkey-b"))
+	backend.SetPubKey("c", []byte("pubkey-c"))
+
+	var verifiedPubkeys [][]byte
+	backend.SetVerifyAggregate(func(pubkeys [][]byte, _, _ []byte) error {
+		verifiedPubkeys = pubkeys
+
+		return nil
+	})
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	if err := vm.Init(1); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	// Bits 0 and 2 set: participating validators are sorted indices 0 ("a") and 2 ("c")
+	proof := AggregateQuorumProof{Bitmap: []byte{0b00000101}, Signature: []byte("sig"), Height: 1}
+
+	// Quorum for totalVotingPower=3 is FLOOR(2*3/3)+1 = 3, which "a"+"c" (2) cannot reach
+	ok, err := vm.HasAggregateQuorum(proof, []byte("msg"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ok {
+		t.Fatal("expected quorum not to be reached with only 2 of 3 voting power participating")
+	}
+
+	if verifiedPubkeys != nil {
+		t.Fatal("expected VerifyAggregate not to be called when quorum is not reached")
+	}
+
+	// Set every bit so all three validators participate, now reaching quorum (3 >= 3)
+	proof.Bitmap = []byte{0b00000111}
+
+	ok, err = vm.HasAggregateQuorum(proof, []byte("msg"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected quorum to be reached with all voting power participating")
+	}
+
+	want := [][]byte{[]byte("pubkey-a"), []byte("pubkey-b"), []byte("pubkey-c")}
+	if len(verifiedPubkeys) != len(want) {
+		t.Fatalf("expected VerifyAggregate to receive %d pubkeys, got %d", len(want), len(verifiedPubkeys))
+	}
+
+	for i, pubkey := range want {
+		if string(verifiedPubkeys[i]) != string(pubkey) {
+			t.Fatalf("pubkey at index %d = %q, want %q (bitmap index must map to the sorted validator)",
+				i, verifiedPubkeys[i], pubkey)
+		}
+	}
+}
+
+// TestHasAggregateQuorum_HeightMismatch checks that a proof produced for a height other than the
+// current one is rejected rather than silently verified against the wrong validator set
+func TestHasAggregateQuorum_HeightMismatch(t *testing.T) {
+	backend := NewInMemoryValidatorBackend("a", map[string]*big.Int{"a": big.NewInt(1)})
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	if err := vm.Init(5); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	_, err = vm.HasAggregateQuorum(AggregateQuorumProof{Height: 4}, []byte("msg"))
+	if !errors.Is(err, errAggregateProofHeightMismatch) {
+		t.Fatalf("expected errAggregateProofHeightMismatch, got %v", err)
+	}
+}
+
+// TestHasAggregateQuorum_UnsupportedBackend checks that HasAggregateQuorum reports an explicit
+// error, rather than panicking or silently failing, when the backend doesn't implement
+// SignatureScheme
+func TestHasAggregateQuorum_UnsupportedBackend(t *testing.T) {
+	backend := &epochBackend{
+		minerAddress: "a",
+		votingPowers: map[string]*big.Int{"a": big.NewInt(1)},
+	}
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	if err := vm.Init(1); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	_, err = vm.HasAggregateQuorum(AggregateQuorumProof{Height: 1}, []byte("msg"))
+	if !errors.Is(err, errAggregateQuorumUnsupported) {
+		t.Fatalf("expected errAggregateQuorumUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/0xPolygon/go-ibft/messages/proto"
+)
+
+// defaultEvidenceViewCapacity bounds how many distinct (height, round, stage) views are tracked
+// for evidence at once, to cap memory usage; the oldest tracked view is evicted once the capacity
+// is exceeded
+const defaultEvidenceViewCapacity = 256
+
+var errNilMessageView = errors.New("message or message view is not set")
+
+// DoubleSignEvidence is produced when a validator is observed signing two conflicting messages
+// for the same (height, round, stage)
+type DoubleSignEvidence struct {
+	Validator string
+	Height    uint64
+	Round     uint64
+	MsgA      *proto.Message
+	MsgB      *proto.Message
+}
+
+// EvidenceReporter is an optional capability a ValidatorBackend may additionally implement to
+// receive DoubleSignEvidence when ValidatorManager detects a validator signing two conflicting
+// messages for the same (height, round, stage), so the application can act on it (e.g. slash). It
+// is deliberately not part of ValidatorBackend itself, so backends that don't want evidence
+// reporting are not forced to implement it; SubmitMessage type-asserts the backend to this
+// interface and drops the evidence when it is absent
+type EvidenceReporter interface {
+	ReportEvidence(evidence DoubleSignEvidence) error
+}
+
+// evidenceView identifies a single (height, round, stage) view
+type evidenceView struct {
+	Height uint64
+	Round  uint64
+	Stage  proto.MessageType
+}
+
+// evidenceVote identifies a single validator's vote within a view
+type evidenceVote struct {
+	evidenceView
+	Validator string
+}
+
+// evidenceStore tracks the first vote seen per (view, validator), bounded to the most
+// recent defaultEvidenceViewCapacity views, and guarantees that a given offense is only reported once
+type evidenceStore struct {
+	lock sync.Mutex
+
+	capacity  int
+	viewOrder []evidenceView
+
+	votes            map[evidenceVote]*proto.Message
+	validatorsByView map[evidenceView][]string
+	reported         map[evidenceVote]struct{}
+}
+
+func newEvidenceStore(capacity int) *evidenceStore {
+	return &evidenceStore{
+		capacity:         capacity,
+		votes:            make(map[evidenceVote]*proto.Message),
+		validatorsByView: make(map[evidenceView][]string),
+		reported:         make(map[evidenceVote]struct{}),
+	}
+}
+
+// submit records msg as validator's vote for view. If validator already has a different vote
+// recorded for this exact view, and this offense has not already been reported, evidence of the
+// double sign is returned
+func (s *evidenceStore) submit(view evidenceView, validator string, msg *proto.Message) (*DoubleSignEvidence, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.trackView(view)
+
+	vote := evidenceVote{evidenceView: view, Validator: validator}
+
+	existing, seen := s.votes[vote]
+	if !seen {
+		s.votes[vote] = msg
+		s.validatorsByView[view] = append(s.validatorsByView[view], validator)
+
+		return nil, false
+	}
+
+	if bytes.Equal(existing.Signature, msg.Signature) {
+		// The same vote was resubmitted, not a conflict
+		return nil, false
+	}
+
+	if _, alreadyReported := s.reported[vote]; alreadyReported {
+		return nil, false
+	}
+
+	s.reported[vote] = struct{}{}
+
+	return &DoubleSignEvidence{
+		Validator: validator,
+		Height:    view.Height,
+		Round:     view.Round,
+		MsgA:      existing,
+		MsgB:      msg,
+	}, true
+}
+
+// trackView registers view as (one of) the most recently seen views, evicting the oldest
+// tracked view's votes once capacity is exceeded. The caller must hold s.lock
+func (s *evidenceStore) trackView(view evidenceView) {
+	if _, ok := s.validatorsByView[view]; ok {
+		return
+	}
+
+	s.viewOrder = append(s.viewOrder, view)
+	s.validatorsByView[view] = nil
+
+	if len(s.viewOrder) <= s.capacity {
+		return
+	}
+
+	oldest := s.viewOrder[0]
+	s.viewOrder = s.viewOrder[1:]
+
+	for _, validator := range s.validatorsByView[oldest] {
+		vote := evidenceVote{evidenceView: oldest, Validator: validator}
+		delete(s.votes, vote)
+		delete(s.reported, vote)
+	}
+
+	delete(s.validatorsByView, oldest)
+}
+
+// SubmitMessage records m as a vote for its (height, round, stage, sender) and, if it conflicts
+// with a previously recorded message for the same view, reports DoubleSignEvidence to the backend.
+// It is called internally from the HasPrepareQuorum/HasQuorumFromMessages paths, but may also be
+// called directly; plain HasQuorum only receives addresses and cannot submit messages itself
+func (vm *ValidatorManager) SubmitMessage(m *proto.Message) error {
+	if m == nil || m.View == nil {
+		return errNilMessageView
+	}
+
+	view := evidenceView{Height: m.View.Height, Round: m.View.Round, Stage: m.Type}
+	validator := string(m.From)
+
+	evidence, found := vm.evidence.submit(view, validator, m)
+	if !found {
+		return nil
+	}
+
+	reporter, ok := vm.backend.(EvidenceReporter)
+	if !ok {
+		return nil
+	}
+
+	return reporter.ReportEvidence(*evidence)
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/go-ibft/messages/proto"
+)
+
+// TestSubmitMessage_DoubleSignDetectedAndReportedOnce checks that two conflicting messages from the
+// same validator for the same (height, round, stage) produce DoubleSignEvidence exactly once, and
+// that resubmitting either message again does not report it a second time
+func TestSubmitMessage_DoubleSignDetectedAndReportedOnce(t *testing.T) {
+	backend := NewInMemoryValidatorBackend("validator-1", map[string]*big.Int{
+		"validator-1": big.NewInt(1),
+	})
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	view := &proto.View{Height: 5, Round: 2}
+	msgA := &proto.Message{View: view, Type: proto.MessageType_PREPARE, From: []byte("validator-1"), Signature: []byte("sig-a")}
+	msgB := &proto.Message{View: view, Type: proto.MessageType_PREPARE, From: []byte("validator-1"), Signature: []byte("sig-b")}
+
+	if err := vm.SubmitMessage(msgA); err != nil {
+		t.Fatalf("unexpected error submitting first message: %v", err)
+	}
+
+	if err := vm.SubmitMessage(msgB); err != nil {
+		t.Fatalf("unexpected error submitting conflicting message: %v", err)
+	}
+
+	evidence := backend.Evidence()
+	if len(evidence) != 1 {
+		t.Fatalf("expected exactly one DoubleSignEvidence to be reported, got %d", len(evidence))
+	}
+
+	if evidence[0].Validator != "validator-1" || evidence[0].Height != 5 || evidence[0].Round != 2 {
+		t.Fatalf("unexpected evidence contents: %+v", evidence[0])
+	}
+
+	// Resubmitting either message again must not report the same offense twice
+	if err := vm.SubmitMessage(msgA); err != nil {
+		t.Fatalf("unexpected error resubmitting first message: %v", err)
+	}
+
+	if err := vm.SubmitMessage(msgB); err != nil {
+		t.Fatalf("unexpected error resubmitting conflicting message: %v", err)
+	}
+
+	if got := len(backend.Evidence()); got != 1 {
+		t.Fatalf("expected the offense to still be reported only once, got %d reports", got)
+	}
+}
+
+// TestSubmitMessage_SameVoteResubmittedIsNotEvidence checks that submitting the exact same message
+// twice (same signature) for a view is not treated as a conflicting vote
+func TestSubmitMessage_SameVoteResubmittedIsNotEvidence(t *testing.T) {
+	backend := NewInMemoryValidatorBackend("validator-1", map[string]*big.Int{
+		"validator-1": big.NewInt(1),
+	})
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	msg := &proto.Message{
+		View:      &proto.View{Height: 1, Round: 0},
+		Type:      proto.MessageType_COMMIT,
+		From:      []byte("validator-1"),
+		Signature: []byte("sig-a"),
+	}
+
+	if err := vm.SubmitMessage(msg); err != nil {
+		t.Fatalf("unexpected error submitting message: %v", err)
+	}
+
+	if err := vm.SubmitMessage(msg); err != nil {
+		t.Fatalf("unexpected error resubmitting identical message: %v", err)
+	}
+
+	if got := len(backend.Evidence()); got != 0 {
+		t.Fatalf("expected no evidence for a resubmitted identical message, got %d reports", got)
+	}
+}
+
+// TestSubmitMessage_WithoutEvidenceReporter checks that SubmitMessage does not error when the
+// backend does not implement EvidenceReporter, since that capability is optional
+func TestSubmitMessage_WithoutEvidenceReporter(t *testing.T) {
+	backend := &epochBackend{
+		minerAddress: "validator-1",
+		votingPowers: map[string]*big.Int{"validator-1": big.NewInt(1)},
+	}
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	view := &proto.View{Height: 1, Round: 0}
+	msgA := &proto.Message{View: view, Type: proto.MessageType_PREPARE, From: []byte("validator-1"), Signature: []byte("sig-a")}
+	msgB := &proto.Message{View: view, Type: proto.MessageType_PREPARE, From: []byte("validator-1"), Signature: []byte("sig-b")}
+
+	if err := vm.SubmitMessage(msgA); err != nil {
+		t.Fatalf("unexpected error submitting first message: %v", err)
+	}
+
+	if err := vm.SubmitMessage(msgB); err != nil {
+		t.Fatalf("expected no error reporting a double-sign when the backend lacks EvidenceReporter, got: %v", err)
+	}
+}
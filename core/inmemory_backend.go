@@ -0,0 +1,127 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+)
+
+var errUnknownValidator = errors.New("unknown validator address")
+
+// InMemoryValidatorBackend is a small in-memory ValidatorBackend, intended for use in tests and
+// devnets (e.g. alongside Misbehavior) without requiring a full node backend
+type InMemoryValidatorBackend struct {
+	lock sync.Mutex
+
+	minerAddress string
+	votingPowers map[string]*big.Int
+	pubkeys      map[string][]byte
+	evidence     []DoubleSignEvidence
+
+	verifyAggregate func(pubkeys [][]byte, msg, sig []byte) error
+}
+
+// NewInMemoryValidatorBackend creates an InMemoryValidatorBackend for the given miner address and
+// initial validator set. Voting powers are always reported for every height, and VerifyAggregate
+// accepts any signature, unless overridden with SetVerifyAggregate
+func NewInMemoryValidatorBackend(minerAddress string, votingPowers map[string]*big.Int) *InMemoryValidatorBackend {
+	copied := make(map[string]*big.Int, len(votingPowers))
+	for addr, votingPower := range votingPowers {
+		copied[addr] = votingPower
+	}
+
+	return &InMemoryValidatorBackend{
+		minerAddress: minerAddress,
+		votingPowers: copied,
+		pubkeys:      make(map[string][]byte),
+	}
+}
+
+// GetVotingPowers implements the ValidatorBackend interface
+func (b *InMemoryValidatorBackend) GetVotingPowers(_ uint64) (map[string]*big.Int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	copied := make(map[string]*big.Int, len(b.votingPowers))
+	for addr, votingPower := range b.votingPowers {
+		copied[addr] = votingPower
+	}
+
+	return copied, nil
+}
+
+// GetMinerAddress implements the ValidatorBackend interface
+func (b *InMemoryValidatorBackend) GetMinerAddress() (string, error) {
+	return b.minerAddress, nil
+}
+
+// GetValidatorSetEpoch implements the ValidatorSetEpochProvider interface. The in-memory backend
+// has a single, never-changing epoch
+func (b *InMemoryValidatorBackend) GetValidatorSetEpoch(_ uint64) (uint64, uint64, error) {
+	return 0, 0, nil
+}
+
+// ReportEvidence implements the EvidenceReporter interface, recording ev for later inspection via Evidence
+func (b *InMemoryValidatorBackend) ReportEvidence(ev DoubleSignEvidence) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.evidence = append(b.evidence, ev)
+
+	return nil
+}
+
+// Evidence returns the evidence reported to this backend so far
+func (b *InMemoryValidatorBackend) Evidence() []DoubleSignEvidence {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	evidence := make([]DoubleSignEvidence, len(b.evidence))
+	copy(evidence, b.evidence)
+
+	return evidence
+}
+
+// SetPubKey registers the public key returned for addr by PubKey
+func (b *InMemoryValidatorBackend) SetPubKey(addr string, pubkey []byte) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.pubkeys[addr] = pubkey
+}
+
+// PubKey implements the SignatureScheme interface
+func (b *InMemoryValidatorBackend) PubKey(addr string) ([]byte, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	pubkey, ok := b.pubkeys[addr]
+	if !ok {
+		return nil, errUnknownValidator
+	}
+
+	return pubkey, nil
+}
+
+// SetVerifyAggregate overrides the default accept-all VerifyAggregate behavior
+func (b *InMemoryValidatorBackend) SetVerifyAggregate(verify func(pubkeys [][]byte, msg, sig []byte) error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.verifyAggregate = verify
+}
+
+// VerifyAggregate implements the SignatureScheme interface. By default it accepts any signature,
+// which is sufficient for tests that only care about quorum/bitmap logic; use SetVerifyAggregate
+// to plug in real verification
+func (b *InMemoryValidatorBackend) VerifyAggregate(pubkeys [][]byte, msg, sig []byte) error {
+	b.lock.Lock()
+	verify := b.verifyAggregate
+	b.lock.Unlock()
+
+	if verify == nil {
+		return nil
+	}
+
+	return verify(pubkeys, msg, sig)
+}
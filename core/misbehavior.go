@@ -0,0 +1,212 @@
+package core
+
+import (
+	"github.com/0xPolygon/go-ibft/messages/proto"
+)
+
+// HeightRound identifies a single height/round pair
+type HeightRound struct {
+	Height uint64
+	Round  uint64
+}
+
+// MisbehaviorToggles configures which faulty behaviors are active for a given HeightRound
+type MisbehaviorToggles struct {
+	// DoublePrepare causes EmitPrepare to emit two conflicting prepare messages instead of one
+	DoublePrepare bool
+
+	// DoubleCommit causes EmitCommit to emit two conflicting commit messages instead of one
+	DoubleCommit bool
+
+	// EquivocateProposal causes EmitProposal to emit two conflicting proposals instead of one
+	EquivocateProposal bool
+
+	// WithholdVote causes the relevant Emit* call to emit no messages at all
+	WithholdVote bool
+
+	// VoteForConflictingProposal causes EmitVoteForProposal to vote for the conflicting proposal
+	// it is given instead of the honest one
+	VoteForConflictingProposal bool
+}
+
+// MisbehaviorConfig maps the (height, round) pairs at which faulty behavior should be injected to
+// the behavior to inject
+type MisbehaviorConfig struct {
+	Triggers map[HeightRound]MisbehaviorToggles
+}
+
+func (c MisbehaviorConfig) toggles(view HeightRound) MisbehaviorToggles {
+	return c.Triggers[view]
+}
+
+// MisbehaviorObserver is notified whenever a configured misbehavior trigger fires, so tests can
+// assert on what was emitted
+type MisbehaviorObserver func(view HeightRound, behavior string, emitted []*proto.Message)
+
+// Misbehavior wraps a ValidatorManager so integrators can deterministically simulate faulty
+// validators in tests and devnets, inspired by Tendermint's "maverick" test node
+type Misbehavior struct {
+	*ValidatorManager
+
+	cfg      MisbehaviorConfig
+	observer MisbehaviorObserver
+
+	// currentRound is the round most recently passed to SetRound, used together with the embedded
+	// ValidatorManager's current height to resolve which MisbehaviorToggles apply to HasQuorum and
+	// HasPrepareQuorum, neither of which otherwise receive a round
+	currentRound uint64
+}
+
+// NewValidatorManagerWithMisbehavior creates a Misbehavior-wrapped ValidatorManager that injects
+// the faulty behaviors configured in cfg at their configured (height, round)
+func NewValidatorManagerWithMisbehavior(
+	backend ValidatorBackend,
+	log Logger,
+	cfg MisbehaviorConfig,
+) (*Misbehavior, error) {
+	vm, err := NewValidatorManager(backend, log, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Misbehavior{ValidatorManager: vm, cfg: cfg}, nil
+}
+
+// SetObserver registers the callback notified when a configured misbehavior trigger fires
+func (m *Misbehavior) SetObserver(observer MisbehaviorObserver) {
+	m.observer = observer
+}
+
+// SetRound records the round currently being processed, so HasQuorum and HasPrepareQuorum can
+// resolve the right MisbehaviorToggles for it. Callers should call this whenever they advance round
+func (m *Misbehavior) SetRound(round uint64) {
+	m.currentRound = round
+}
+
+// currentView returns the (height, round) HasQuorum/HasPrepareQuorum should evaluate misbehavior
+// triggers against
+func (m *Misbehavior) currentView() HeightRound {
+	return HeightRound{Height: m.currentHeight, Round: m.currentRound}
+}
+
+// HasQuorum overrides ValidatorManager.HasQuorum: if WithholdVote is configured for the current
+// (height, round), quorum is reported as not reached and the observer is notified; otherwise it
+// delegates to the embedded ValidatorManager
+func (m *Misbehavior) HasQuorum(sendersAddrs map[string]struct{}) bool {
+	view := m.currentView()
+
+	if m.cfg.toggles(view).WithholdVote {
+		m.notify(view, "quorum:withhold", nil)
+
+		return false
+	}
+
+	return m.ValidatorManager.HasQuorum(sendersAddrs)
+}
+
+// HasPrepareQuorum overrides ValidatorManager.HasPrepareQuorum: if WithholdVote is configured for
+// the current (height, round), quorum is reported as not reached and the observer is notified;
+// otherwise it delegates to the embedded ValidatorManager
+func (m *Misbehavior) HasPrepareQuorum(stateName stateType, proposalMessage *proto.Message, msgs []*proto.Message) bool {
+	view := m.currentView()
+
+	if m.cfg.toggles(view).WithholdVote {
+		m.notify(view, "quorum:withhold", nil)
+
+		return false
+	}
+
+	return m.ValidatorManager.HasPrepareQuorum(stateName, proposalMessage, msgs)
+}
+
+// EmitProposal returns the proposal(s) that should be emitted for (height, round): two conflicting
+// proposals if EquivocateProposal is configured, none if WithholdVote is configured, or the single
+// honest proposal built by honest otherwise
+func (m *Misbehavior) EmitProposal(height, round uint64, honest, conflicting func() *proto.Message) []*proto.Message {
+	return m.emit(height, round, "proposal", honest, conflicting)
+}
+
+// EmitPrepare returns the prepare message(s) that should be emitted for (height, round): two
+// conflicting messages if DoublePrepare is configured, none if WithholdVote is configured, or the
+// single honest message built by honest otherwise
+func (m *Misbehavior) EmitPrepare(height, round uint64, honest, conflicting func() *proto.Message) []*proto.Message {
+	return m.emit(height, round, "prepare", honest, conflicting)
+}
+
+// EmitCommit returns the commit message(s) that should be emitted for (height, round): two
+// conflicting messages if DoubleCommit is configured, none if WithholdVote is configured, or the
+// single honest message built by honest otherwise
+func (m *Misbehavior) EmitCommit(height, round uint64, honest, conflicting func() *proto.Message) []*proto.Message {
+	return m.emit(height, round, "commit", honest, conflicting)
+}
+
+func (m *Misbehavior) emit(
+	height, round uint64,
+	behavior string,
+	honest, conflicting func() *proto.Message,
+) []*proto.Message {
+	view := HeightRound{Height: height, Round: round}
+	toggles := m.cfg.toggles(view)
+
+	if toggles.WithholdVote {
+		m.notify(view, behavior+":withhold", nil)
+
+		return nil
+	}
+
+	doubleVote := (behavior == "prepare" && toggles.DoublePrepare) ||
+		(behavior == "commit" && toggles.DoubleCommit) ||
+		(behavior == "proposal" && toggles.EquivocateProposal)
+
+	if doubleVote && conflicting != nil {
+		emitted := []*proto.Message{honest(), conflicting()}
+		m.notify(view, behavior+":double", emitted)
+
+		return emitted
+	}
+
+	emitted := []*proto.Message{honest()}
+	m.notify(view, behavior, emitted)
+
+	return emitted
+}
+
+// EmitVoteForProposal returns the message a validator should cast for (height, round) via build,
+// voting for conflictingProposal instead of honestProposal when VoteForConflictingProposal is
+// configured, or nil if WithholdVote is configured
+func (m *Misbehavior) EmitVoteForProposal(
+	height, round uint64,
+	honestProposal, conflictingProposal *proto.Message,
+	build func(proposal *proto.Message) *proto.Message,
+) *proto.Message {
+	view := HeightRound{Height: height, Round: round}
+	toggles := m.cfg.toggles(view)
+
+	if toggles.WithholdVote {
+		m.notify(view, "vote:withhold", nil)
+
+		return nil
+	}
+
+	proposal := honestProposal
+	behavior := "vote:honest"
+
+	if toggles.VoteForConflictingProposal && conflictingProposal != nil {
+		proposal = conflictingProposal
+		behavior = "vote:conflicting"
+	}
+
+	vote := build(proposal)
+
+	m.notify(view, behavior, []*proto.Message{vote})
+
+	return vote
+}
+
+func (m *Misbehavior) notify(view HeightRound, behavior string, emitted []*proto.Message) {
+	if m.observer == nil {
+		return
+	}
+
+	m.observer(view, behavior, emitted)
+}
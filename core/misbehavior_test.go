@@ -0,0 +1,109 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygon/go-ibft/messages/proto"
+)
+
+// noopLogger is a minimal Logger for tests that don't care about log output
+type noopLogger struct{}
+
+func (noopLogger) Info(string, ...interface{})  {}
+func (noopLogger) Error(string, ...interface{}) {}
+func (noopLogger) Debug(string, ...interface{}) {}
+func (noopLogger) Warn(string, ...interface{})  {}
+
+// TestMisbehavior_WithholdVote exercises InMemoryValidatorBackend end-to-end: a withhold-vote
+// trigger at a configured (height, round) makes HasQuorum report no quorum and notify the
+// observer, while other (height, round) pairs behave like a normal ValidatorManager
+func TestMisbehavior_WithholdVote(t *testing.T) {
+	backend := NewInMemoryValidatorBackend("validator-1", map[string]*big.Int{
+		"validator-1": big.NewInt(1),
+		"validator-2": big.NewInt(1),
+		"validator-3": big.NewInt(1),
+		"validator-4": big.NewInt(1),
+	})
+
+	cfg := MisbehaviorConfig{
+		Triggers: map[HeightRound]MisbehaviorToggles{
+			{Height: 1, Round: 0}: {WithholdVote: true},
+		},
+	}
+
+	mb, err := NewValidatorManagerWithMisbehavior(backend, noopLogger{}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error creating misbehavior harness: %v", err)
+	}
+
+	if err := mb.Init(1); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	mb.SetRound(0)
+
+	var observed []string
+	mb.SetObserver(func(_ HeightRound, behavior string, _ []*proto.Message) {
+		observed = append(observed, behavior)
+	})
+
+	// Enough voting power to reach quorum if the trigger did not apply
+	senders := map[string]struct{}{
+		"validator-1": {},
+		"validator-2": {},
+		"validator-3": {},
+	}
+
+	if mb.HasQuorum(senders) {
+		t.Fatal("expected HasQuorum to be withheld at the configured (height, round) trigger")
+	}
+
+	if len(observed) != 1 || observed[0] != "quorum:withhold" {
+		t.Fatalf("expected observer to record quorum:withhold, got %v", observed)
+	}
+
+	// Advancing past the configured round clears the trigger and HasQuorum behaves normally again
+	mb.SetRound(1)
+
+	if !mb.HasQuorum(senders) {
+		t.Fatal("expected HasQuorum to report quorum reached once outside the configured trigger")
+	}
+}
+
+// TestMisbehavior_EmitPrepare_DoublePrepare checks that a DoublePrepare trigger produces two
+// conflicting prepare messages and notifies the observer accordingly
+func TestMisbehavior_EmitPrepare_DoublePrepare(t *testing.T) {
+	backend := NewInMemoryValidatorBackend("validator-1", map[string]*big.Int{
+		"validator-1": big.NewInt(1),
+	})
+
+	cfg := MisbehaviorConfig{
+		Triggers: map[HeightRound]MisbehaviorToggles{
+			{Height: 5, Round: 2}: {DoublePrepare: true},
+		},
+	}
+
+	mb, err := NewValidatorManagerWithMisbehavior(backend, noopLogger{}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error creating misbehavior harness: %v", err)
+	}
+
+	honest := &proto.Message{From: []byte("validator-1"), Signature: []byte("sig-a")}
+	conflicting := &proto.Message{From: []byte("validator-1"), Signature: []byte("sig-b")}
+
+	var observedBehavior string
+	mb.SetObserver(func(_ HeightRound, behavior string, _ []*proto.Message) {
+		observedBehavior = behavior
+	})
+
+	emitted := mb.EmitPrepare(5, 2, func() *proto.Message { return honest }, func() *proto.Message { return conflicting })
+
+	if len(emitted) != 2 {
+		t.Fatalf("expected 2 conflicting prepare messages, got %d", len(emitted))
+	}
+
+	if observedBehavior != "prepare:double" {
+		t.Fatalf("expected observer to record prepare:double, got %q", observedBehavior)
+	}
+}
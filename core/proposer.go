@@ -0,0 +1,146 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+)
+
+// errValidatorManagerNotInitialized is returned when CalcProposer is called before Init has
+// populated the validator set for the current height
+var errValidatorManagerNotInitialized = errors.New("validator manager is not initialized")
+
+// CalcProposer calculates the proposer for the given round of the current height, using a
+// Tendermint-style, stake-weighted accumulator: each validator's priority is incremented by its
+// voting power every round, the validator with the highest priority is selected (ties broken by
+// address bytes), and the total voting power is then subtracted from the selected validator's
+// priority. Rounds are calculated incrementally and cached, so callers may query rounds out of
+// order or repeatedly without skewing the accumulator
+func (vm *ValidatorManager) CalcProposer(round uint64) (string, error) {
+	vm.proposerLock.Lock()
+	defer vm.proposerLock.Unlock()
+
+	vm.vpLock.RLock()
+	votingPowers := vm.validatorsVotingPower
+	totalVotingPower := vm.totalVotingPower
+	vm.vpLock.RUnlock()
+
+	if votingPowers == nil {
+		return "", errValidatorManagerNotInitialized
+	}
+
+	if proposer, ok := vm.roundProposers[round]; ok {
+		return proposer, nil
+	}
+
+	for r := vm.lastCalculatedRound + 1; r <= int64(round); r++ {
+		proposer := vm.advanceProposerPriority(votingPowers, totalVotingPower)
+		vm.roundProposers[uint64(r)] = proposer
+		vm.lastCalculatedRound = r
+	}
+
+	return vm.roundProposers[round], nil
+}
+
+// advanceProposerPriority runs a single tick of the proposer selection accumulator and returns
+// the validator selected for that tick. The caller must hold proposerLock
+func (vm *ValidatorManager) advanceProposerPriority(
+	votingPowers map[string]*big.Int,
+	totalVotingPower *big.Int,
+) string {
+	for addr, votingPower := range votingPowers {
+		priority, ok := vm.proposerPriorities[addr]
+		if !ok {
+			priority = big.NewInt(0)
+			vm.proposerPriorities[addr] = priority
+		}
+
+		priority.Add(priority, votingPower)
+	}
+
+	var (
+		proposer string
+		highest  *big.Int
+	)
+
+	for addr, priority := range vm.proposerPriorities {
+		if _, isValidator := votingPowers[addr]; !isValidator {
+			continue
+		}
+
+		switch {
+		case highest == nil:
+			highest, proposer = priority, addr
+		case priority.Cmp(highest) > 0:
+			highest, proposer = priority, addr
+		case priority.Cmp(highest) == 0 && bytes.Compare([]byte(addr), []byte(proposer)) < 0:
+			proposer = addr
+		}
+	}
+
+	vm.proposerPriorities[proposer].Sub(vm.proposerPriorities[proposer], totalVotingPower)
+
+	return proposer
+}
+
+// rollProposerPriorities seeds the proposer priorities for the current height: priorities are
+// carried over for validators that remain in the set, new validators start at zero, and the
+// result is re-centered around zero (and clamped) so that validator set churn cannot cause
+// unbounded drift or an unfairly long catch-up period for a newly added, large-stake validator.
+// The caller must not hold vpLock
+func (vm *ValidatorManager) rollProposerPriorities() {
+	vm.vpLock.RLock()
+	votingPowers := vm.validatorsVotingPower
+	totalVotingPower := vm.totalVotingPower
+	vm.vpLock.RUnlock()
+
+	vm.proposerLock.Lock()
+	defer vm.proposerLock.Unlock()
+
+	rolled := make(map[string]*big.Int, len(votingPowers))
+
+	for addr := range votingPowers {
+		if previous, ok := vm.proposerPriorities[addr]; ok {
+			rolled[addr] = new(big.Int).Set(previous)
+		} else {
+			rolled[addr] = big.NewInt(0)
+		}
+	}
+
+	vm.proposerPriorities = rolled
+	vm.recenterProposerPriorities(totalVotingPower)
+
+	vm.roundProposers = make(map[uint64]string)
+	vm.lastCalculatedRound = -1
+}
+
+// recenterProposerPriorities shifts all priorities by their average, so they sum to (approximately)
+// zero, and clamps each priority to [-2*totalVotingPower, 2*totalVotingPower]. The caller must hold
+// proposerLock
+func (vm *ValidatorManager) recenterProposerPriorities(totalVotingPower *big.Int) {
+	if len(vm.proposerPriorities) == 0 {
+		return
+	}
+
+	sum := big.NewInt(0)
+	for _, priority := range vm.proposerPriorities {
+		sum.Add(sum, priority)
+	}
+
+	count := big.NewInt(int64(len(vm.proposerPriorities)))
+	average := new(big.Int).Div(sum, count)
+
+	bound := new(big.Int).Mul(totalVotingPower, big.NewInt(proposerPriorityBoundMultiplier))
+	lowerBound := new(big.Int).Neg(bound)
+
+	for addr, priority := range vm.proposerPriorities {
+		priority.Sub(priority, average)
+
+		switch {
+		case priority.Cmp(bound) > 0:
+			vm.proposerPriorities[addr] = new(big.Int).Set(bound)
+		case priority.Cmp(lowerBound) < 0:
+			vm.proposerPriorities[addr] = new(big.Int).Set(lowerBound)
+		}
+	}
+}
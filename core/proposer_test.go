@@ -0,0 +1,101 @@
+package core
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+// TestCalcProposer_ProportionalToVotingPower checks that, over a full accumulator cycle, each
+// validator is selected proposer proportionally to its voting power
+func TestCalcProposer_ProportionalToVotingPower(t *testing.T) {
+	backend := NewInMemoryValidatorBackend("validatorA", map[string]*big.Int{
+		"validatorA": big.NewInt(1),
+		"validatorB": big.NewInt(3),
+	})
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	if err := vm.Init(1); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	counts := map[string]int{}
+
+	const rounds = 4
+
+	for round := uint64(0); round < rounds; round++ {
+		proposer, err := vm.CalcProposer(round)
+		if err != nil {
+			t.Fatalf("unexpected error calculating proposer for round %d: %v", round, err)
+		}
+
+		counts[proposer]++
+	}
+
+	// Total voting power is 4, so over 4 rounds validatorB (voting power 3) should be selected
+	// 3 times and validatorA (voting power 1) once
+	if counts["validatorB"] != 3 || counts["validatorA"] != 1 {
+		t.Fatalf("expected proposer counts proportional to voting power, got %v", counts)
+	}
+}
+
+// TestCalcProposer_TieBrokenByAddress checks that when two validators have equal priority, the
+// validator with the lower address bytes is selected
+func TestCalcProposer_TieBrokenByAddress(t *testing.T) {
+	backend := NewInMemoryValidatorBackend("aaa", map[string]*big.Int{
+		"aaa": big.NewInt(1),
+		"zzz": big.NewInt(1),
+	})
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	if err := vm.Init(1); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	// Both validators start at priority 0, so incrementing by equal voting power produces a tie,
+	// which must be broken by address bytes ("aaa" < "zzz")
+	proposer, err := vm.CalcProposer(0)
+	if err != nil {
+		t.Fatalf("unexpected error calculating proposer: %v", err)
+	}
+
+	if proposer != "aaa" {
+		t.Fatalf("expected tie to be broken in favor of the lower address, got %q", proposer)
+	}
+}
+
+// TestRecenterProposerPriorities_ClampsToBound checks that a priority which has drifted beyond
+// proposerPriorityBoundMultiplier*totalVotingPower (e.g. from a newly joined, large-stake
+// validator) is clamped rather than left to skew selection for an unbounded number of rounds
+func TestRecenterProposerPriorities_ClampsToBound(t *testing.T) {
+	vm := &ValidatorManager{
+		proposerLock: &sync.Mutex{},
+		proposerPriorities: map[string]*big.Int{
+			"small-stake-a": big.NewInt(1000),
+			"small-stake-b": big.NewInt(-1000),
+			"new-large":     big.NewInt(0),
+		},
+	}
+
+	totalVotingPower := big.NewInt(10)
+	bound := new(big.Int).Mul(totalVotingPower, big.NewInt(proposerPriorityBoundMultiplier))
+	lowerBound := new(big.Int).Neg(bound)
+
+	vm.proposerLock.Lock()
+	vm.recenterProposerPriorities(totalVotingPower)
+	vm.proposerLock.Unlock()
+
+	for addr, priority := range vm.proposerPriorities {
+		if priority.Cmp(bound) > 0 || priority.Cmp(lowerBound) < 0 {
+			t.Fatalf("priority for %q = %s, want within [%s, %s]", addr, priority, lowerBound, bound)
+		}
+	}
+}
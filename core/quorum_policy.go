@@ -0,0 +1,108 @@
+package core
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// QuorumPolicy determines the minimum cumulative voting power required for quorum, given the
+// total voting power of the current validator set. Implementations allow chains to change their
+// quorum rules (e.g. for a fork) without requiring changes to this module
+type QuorumPolicy interface {
+	// Quorum returns the minimum cumulative voting power required for quorum
+	Quorum(totalVotingPower *big.Int) *big.Int
+}
+
+// FloorTwoThirdsPlusOne is the historical quorum policy: FLOOR(2 * totalVotingPower / 3) + 1
+type FloorTwoThirdsPlusOne struct{}
+
+// Quorum implements the QuorumPolicy interface
+func (FloorTwoThirdsPlusOne) Quorum(totalVotingPower *big.Int) *big.Int {
+	return calculateQuorum(totalVotingPower)
+}
+
+// CeilTwoThirds is a quorum policy of CEIL(2 * totalVotingPower / 3), as used by some
+// Istanbul/QIBFT deployments
+type CeilTwoThirds struct{}
+
+// Quorum implements the QuorumPolicy interface
+func (CeilTwoThirds) Quorum(totalVotingPower *big.Int) *big.Int {
+	quorum := new(big.Int).Mul(totalVotingPower, big.NewInt(2))
+
+	remainder := new(big.Int)
+	quorum.DivMod(quorum, big.NewInt(3), remainder)
+
+	if remainder.Sign() != 0 {
+		quorum.Add(quorum, big.NewInt(1))
+	}
+
+	return quorum
+}
+
+// QuorumPolicyFork pairs a QuorumPolicy with the block height at which it becomes active
+type QuorumPolicyFork struct {
+	// Block is the height at which Policy becomes active
+	Block uint64
+
+	// Policy is the QuorumPolicy to use from Block onwards, until the next configured fork
+	Policy QuorumPolicy
+}
+
+// ForkedQuorumPolicy switches between QuorumPolicy implementations at configured block heights,
+// analogous to the Ceil2Nby3Block/QibftBlock fork-block pattern used by Istanbul/QIBFT deployments.
+// It must be informed of the current height via SetHeight before Quorum is called; ValidatorManager
+// does this automatically on every Init
+type ForkedQuorumPolicy struct {
+	lock   sync.Mutex
+	forks  []QuorumPolicyFork
+	height uint64
+}
+
+// NewForkedQuorumPolicy creates a ForkedQuorumPolicy from the given forks. Forks do not need to be
+// passed in any particular order. Heights not covered by any fork (i.e. below the lowest configured
+// Block) fall back to FloorTwoThirdsPlusOne
+func NewForkedQuorumPolicy(forks ...QuorumPolicyFork) *ForkedQuorumPolicy {
+	sorted := make([]QuorumPolicyFork, len(forks))
+	copy(sorted, forks)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Block < sorted[j].Block
+	})
+
+	return &ForkedQuorumPolicy{forks: sorted}
+}
+
+// SetHeight updates the height used to resolve which configured QuorumPolicy is active. It must be
+// called before Quorum for the height change to take effect
+func (p *ForkedQuorumPolicy) SetHeight(height uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.height = height
+}
+
+// Quorum implements the QuorumPolicy interface, delegating to whichever policy is active for the
+// height most recently passed to SetHeight
+func (p *ForkedQuorumPolicy) Quorum(totalVotingPower *big.Int) *big.Int {
+	p.lock.Lock()
+	height := p.height
+	p.lock.Unlock()
+
+	return p.activePolicy(height).Quorum(totalVotingPower)
+}
+
+// activePolicy returns the policy configured for the given height
+func (p *ForkedQuorumPolicy) activePolicy(height uint64) QuorumPolicy {
+	active := QuorumPolicy(FloorTwoThirdsPlusOne{})
+
+	for _, fork := range p.forks {
+		if height < fork.Block {
+			break
+		}
+
+		active = fork.Policy
+	}
+
+	return active
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestForkedQuorumPolicy_BoundaryBlock checks that ForkedQuorumPolicy switches policies exactly at
+// the configured fork block, and not one height early or late
+func TestForkedQuorumPolicy_BoundaryBlock(t *testing.T) {
+	const forkBlock = 100
+
+	policy := NewForkedQuorumPolicy(QuorumPolicyFork{Block: forkBlock, Policy: CeilTwoThirds{}})
+
+	// totalVotingPower = 9 is chosen so FloorTwoThirdsPlusOne and CeilTwoThirds disagree:
+	// FLOOR(2*9/3)+1 = 7, CEIL(2*9/3) = 6
+	totalVotingPower := big.NewInt(9)
+
+	floorQuorum := FloorTwoThirdsPlusOne{}.Quorum(totalVotingPower)
+	ceilQuorum := CeilTwoThirds{}.Quorum(totalVotingPower)
+
+	if floorQuorum.Cmp(ceilQuorum) == 0 {
+		t.Fatalf("test fixture is not discriminating: floor quorum %s equals ceil quorum %s", floorQuorum, ceilQuorum)
+	}
+
+	cases := []struct {
+		height uint64
+		want   *big.Int
+	}{
+		{height: forkBlock - 1, want: floorQuorum},
+		{height: forkBlock, want: ceilQuorum},
+		{height: forkBlock + 1, want: ceilQuorum},
+	}
+
+	for _, c := range cases {
+		policy.SetHeight(c.height)
+
+		if got := policy.Quorum(totalVotingPower); got.Cmp(c.want) != 0 {
+			t.Errorf("height %d: got quorum %s, want %s", c.height, got, c.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/0xPolygon/go-ibft/messages/proto"
@@ -13,6 +14,12 @@ var (
 	errVotingPowerNotCorrect = errors.New("total voting power is zero or less")
 )
 
+// proposerPriorityBoundMultiplier bounds how far a single validator's proposer
+// priority may drift from zero (in multiples of the total voting power), so a
+// newly joined, large-stake validator cannot be starved from proposing, or
+// monopolize proposing, for an unbounded number of rounds
+const proposerPriorityBoundMultiplier = 2
+
 // ValidatorBackend defines an interface that has GetVotingPower and GetMinerAddress
 type ValidatorBackend interface {
 	// GetVotingPowers returns a map of validators' addresses and their voting powers for the specified height.
@@ -22,6 +29,33 @@ type ValidatorBackend interface {
 	GetMinerAddress() (string, error)
 }
 
+// ValidatorSetEpochProvider is an optional capability a ValidatorBackend may additionally implement,
+// letting ValidatorManager skip a GetVotingPowers call and reuse a cached ValidatorSnapshot when the
+// validator set epoch for the requested height has not changed. It is deliberately not part of
+// ValidatorBackend itself, so backends that don't track epochs are not forced to implement it; Init
+// type-asserts the backend to this interface and falls back to always calling GetVotingPowers when
+// it is absent
+type ValidatorSetEpochProvider interface {
+	// GetValidatorSetEpoch returns the epoch the given height belongs to, and the height at which
+	// the validator set for that epoch was last changed. ValidatorManager uses this to decide
+	// whether it can reuse a cached ValidatorSnapshot instead of calling GetVotingPowers again
+	GetValidatorSetEpoch(height uint64) (epoch uint64, changedAt uint64, err error)
+}
+
+// SignatureScheme is an optional capability a ValidatorBackend may additionally implement, for an
+// aggregate/threshold signature scheme (e.g. BLS12-381 or Schnorr-MuSig), so that HasAggregateQuorum
+// can verify a single aggregate signature against a bitmap of participating validators instead of
+// requiring one signature per message. It is deliberately not part of ValidatorBackend itself, so
+// backends that never use aggregate quorums are not forced to implement it; HasAggregateQuorum
+// type-asserts the backend to this interface and reports an explicit error when it is absent
+type SignatureScheme interface {
+	// VerifyAggregate verifies that sig is a valid aggregate signature by all of pubkeys over msg
+	VerifyAggregate(pubkeys [][]byte, msg, sig []byte) error
+
+	// PubKey returns the public key for the validator at addr
+	PubKey(addr string) ([]byte, error)
+}
+
 // ValidatorManager keeps voting power and other information about validators
 type ValidatorManager struct {
 	vpLock *sync.RWMutex
@@ -33,21 +67,67 @@ type ValidatorManager struct {
 	// the height specified in the current View
 	validatorsVotingPower map[string]*big.Int
 
+	// totalVotingPower is the sum of validatorsVotingPower for the height specified in the current View
+	totalVotingPower *big.Int
+
+	// sortedValidators is validatorsVotingPower's keys, sorted by address bytes, computed once per
+	// Init so that AggregateQuorumProof bitmap positions map to addresses in O(1)
+	sortedValidators []string
+
+	// currentHeight is the height passed to the most recent Init call
+	currentHeight uint64
+
+	// currentEpoch and currentChangedAt are the values returned by GetValidatorSetEpoch for
+	// currentHeight, i.e. the snapshotCache key ApplyValidatorDiff must keep in sync
+	currentEpoch     uint64
+	currentChangedAt uint64
+
 	// minerAddress stores the address of the miner
 	minerAddress string
 
 	backend ValidatorBackend
 
 	log Logger
+
+	// quorumPolicy determines the quorum size for a given total voting power. It is resolved
+	// once per Init call, so a ForkedQuorumPolicy can switch implementations at a configured height
+	quorumPolicy QuorumPolicy
+
+	// snapshotCache caches validator sets by epoch, so Init can skip GetVotingPowers when the
+	// epoch for the requested height has not changed since it was last cached
+	snapshotCache *validatorSnapshotCache
+
+	// evidence tracks per-view votes to detect and report double signing
+	evidence *evidenceStore
+
+	proposerLock *sync.Mutex
+
+	// proposerPriorities is the Tendermint-style proposer selection accumulator, keyed by validator address.
+	// It is carried over across heights (for validators that remain in the set) so that proposer selection
+	// stays stake-weighted over time rather than restarting from scratch every height
+	proposerPriorities map[string]*big.Int
+
+	// roundProposers caches the proposer already calculated for a given round in the current height,
+	// so that repeated CalcProposer calls for the same round do not advance the accumulator twice
+	roundProposers map[uint64]string
+
+	// lastCalculatedRound is the highest round for which the proposer accumulator has been advanced
+	// in the current height; -1 means no round has been calculated yet
+	lastCalculatedRound int64
 }
 
-// NewValidatorManager creates a new ValidatorManager
-func NewValidatorManager(backend ValidatorBackend, log Logger) (*ValidatorManager, error) {
+// NewValidatorManager creates a new ValidatorManager. If quorumPolicy is nil, it defaults to
+// FloorTwoThirdsPlusOne, which preserves the historical quorum calculation
+func NewValidatorManager(backend ValidatorBackend, log Logger, quorumPolicy QuorumPolicy) (*ValidatorManager, error) {
 	minerAddress, err := backend.GetMinerAddress()
 	if err != nil {
 		return nil, err
 	}
 
+	if quorumPolicy == nil {
+		quorumPolicy = FloorTwoThirdsPlusOne{}
+	}
+
 	return &ValidatorManager{
 		quorumSize:            big.NewInt(0),
 		backend:               backend,
@@ -55,22 +135,84 @@ func NewValidatorManager(backend ValidatorBackend, log Logger) (*ValidatorManage
 		minerAddress:          minerAddress, // Store the miner address
 		log:                   log,
 		vpLock:                &sync.RWMutex{},
+		quorumPolicy:          quorumPolicy,
+		snapshotCache:         newValidatorSnapshotCache(defaultValidatorSnapshotCacheSize),
+		evidence:              newEvidenceStore(defaultEvidenceViewCapacity),
+		proposerLock:          &sync.Mutex{},
+		proposerPriorities:    make(map[string]*big.Int),
+		roundProposers:        make(map[uint64]string),
+		lastCalculatedRound:   -1,
 	}, nil
 }
 
 // Init sets voting power and quorum size
 func (vm *ValidatorManager) Init(height uint64) error {
-	validatorsVotingPower, err := vm.backend.GetVotingPowers(height)
+	var (
+		epoch, changedAt      uint64
+		validatorsVotingPower map[string]*big.Int
+		err                   error
+	)
+
+	if epochProvider, ok := vm.backend.(ValidatorSetEpochProvider); ok {
+		epoch, changedAt, err = epochProvider.GetValidatorSetEpoch(height)
+		if err != nil {
+			return err
+		}
+
+		validatorsVotingPower, err = vm.loadValidatorsVotingPower(height, epoch, changedAt)
+	} else {
+		// The backend doesn't track epochs, so there is nothing to cache against: always fetch
+		// the current validator set
+		validatorsVotingPower, err = vm.backend.GetVotingPowers(height)
+	}
+
 	if err != nil {
 		return err
 	}
 
-	return vm.setCurrentVotingPower(validatorsVotingPower)
+	if forked, ok := vm.quorumPolicy.(*ForkedQuorumPolicy); ok {
+		forked.SetHeight(height)
+	}
+
+	if err := vm.setCurrentVotingPower(height, epoch, changedAt, validatorsVotingPower); err != nil {
+		return err
+	}
+
+	vm.rollProposerPriorities()
+
+	return nil
 }
 
-// setCurrentVotingPower sets the current total voting power and quorum size
-// based on current validators' voting power
-func (vm *ValidatorManager) setCurrentVotingPower(validatorsVotingPower map[string]*big.Int) error {
+// loadValidatorsVotingPower returns the validator set to use for height. If a ValidatorSnapshot is
+// already cached for epoch and it has not changed since (changedAt matches), the cached set is
+// reused; otherwise the full set is fetched from the backend and the snapshot cache is refreshed
+func (vm *ValidatorManager) loadValidatorsVotingPower(height, epoch, changedAt uint64) (map[string]*big.Int, error) {
+	if snapshot, ok := vm.snapshotCache.get(epoch); ok && snapshot.ChangedAt == changedAt {
+		return snapshot.ValidatorsVotingPower, nil
+	}
+
+	validatorsVotingPower, err := vm.backend.GetVotingPowers(height)
+	if err != nil {
+		return nil, err
+	}
+
+	vm.snapshotCache.put(&ValidatorSnapshot{
+		Epoch:                 epoch,
+		ChangedAt:             changedAt,
+		ValidatorsVotingPower: validatorsVotingPower,
+	})
+
+	return validatorsVotingPower, nil
+}
+
+// setCurrentVotingPower sets the current total voting power, quorum size, and epoch bookkeeping
+// (currentHeight/currentEpoch/currentChangedAt) based on current validators' voting power. These
+// fields are all written together under vpLock because they're read together under vpLock.RLock
+// elsewhere (e.g. HasAggregateQuorum) and under vpLock.Lock() in ApplyValidatorDiff
+func (vm *ValidatorManager) setCurrentVotingPower(
+	height, epoch, changedAt uint64,
+	validatorsVotingPower map[string]*big.Int,
+) error {
 	vm.vpLock.Lock()
 	defer vm.vpLock.Unlock()
 
@@ -80,12 +222,92 @@ func (vm *ValidatorManager) setCurrentVotingPower(validatorsVotingPower map[stri
 	}
 
 	vm.validatorsVotingPower = validatorsVotingPower
-	vm.quorumSize = calculateQuorum(totalVotingPower)
+	vm.totalVotingPower = totalVotingPower
+	vm.quorumSize = vm.quorumPolicy.Quorum(totalVotingPower)
+	vm.sortedValidators = sortedAddresses(validatorsVotingPower)
+	vm.currentHeight = height
+	vm.currentEpoch = epoch
+	vm.currentChangedAt = changedAt
 
 	return nil
 }
 
-// HasQuorum provides information on whether messages have reached the quorum
+// ApplyValidatorDiff incrementally updates the current validator set for backends that can push
+// joins/leaves/stake changes instead of re-reading the full set. quorumSize is recomputed from the
+// delta rather than rescanning the whole validator map. A validator present in both added and
+// removed is treated as an update to its voting power (removed is ignored for that address)
+func (vm *ValidatorManager) ApplyValidatorDiff(added, removed map[string]*big.Int) error {
+	vm.vpLock.Lock()
+
+	if vm.validatorsVotingPower == nil {
+		vm.vpLock.Unlock()
+
+		return errValidatorManagerNotInitialized
+	}
+
+	updated := make(map[string]*big.Int, len(vm.validatorsVotingPower)+len(added))
+	for addr, votingPower := range vm.validatorsVotingPower {
+		updated[addr] = votingPower
+	}
+
+	delta := big.NewInt(0)
+
+	for addr := range removed {
+		if _, ok := added[addr]; ok {
+			// Reported as both added and removed: treat as a voting power update, not a removal
+			continue
+		}
+
+		if existing, ok := updated[addr]; ok {
+			delta.Sub(delta, existing)
+			delete(updated, addr)
+		}
+	}
+
+	for addr, votingPower := range added {
+		if existing, ok := updated[addr]; ok {
+			delta.Sub(delta, existing)
+		}
+
+		delta.Add(delta, votingPower)
+		updated[addr] = votingPower
+	}
+
+	totalVotingPower := new(big.Int).Add(vm.totalVotingPower, delta)
+	if totalVotingPower.Cmp(big.NewInt(0)) <= 0 {
+		vm.vpLock.Unlock()
+
+		return errVotingPowerNotCorrect
+	}
+
+	vm.validatorsVotingPower = updated
+	vm.totalVotingPower = totalVotingPower
+	vm.quorumSize = vm.quorumPolicy.Quorum(totalVotingPower)
+	vm.sortedValidators = sortedAddresses(updated)
+
+	// Keep the epoch snapshot cache in sync with the diff: otherwise the next Init call for the
+	// same (epoch, changedAt) would hit the stale pre-diff entry and silently revert this update
+	vm.snapshotCache.put(&ValidatorSnapshot{
+		Epoch:                 vm.currentEpoch,
+		ChangedAt:             vm.currentChangedAt,
+		ValidatorsVotingPower: updated,
+	})
+
+	vm.vpLock.Unlock()
+
+	// Re-center/prune the proposer priorities too: the clamp bound depends on totalVotingPower,
+	// which just changed, and a removed validator's entry would otherwise linger until the next Init.
+	// This must happen after vpLock is released, since rollProposerPriorities acquires it itself
+	vm.rollProposerPriorities()
+
+	return nil
+}
+
+// HasQuorum provides information on whether messages have reached the quorum. HasQuorum only
+// receives addresses, so it cannot itself submit messages for double-sign evidence tracking (see
+// SubmitMessage): callers that hold the full proto.Message set for a stage (e.g. COMMIT) must call
+// SubmitMessage for each message themselves, or use HasQuorumFromMessages, if that stage should be
+// covered by evidence detection. HasPrepareQuorum already does this for the PREPARE stage
 func (vm *ValidatorManager) HasQuorum(sendersAddrs map[string]struct{}) bool {
 	vm.vpLock.RLock()
 	defer vm.vpLock.RUnlock()
@@ -107,6 +329,19 @@ func (vm *ValidatorManager) HasQuorum(sendersAddrs map[string]struct{}) bool {
 	return messageVotePower.Cmp(vm.quorumSize) >= 0
 }
 
+// HasQuorumFromMessages is a convenience wrapper for quorum checks at stages where the full
+// proto.Message set is available rather than bare addresses (e.g. COMMIT): it submits each message
+// for double-sign evidence tracking before delegating to HasQuorum
+func (vm *ValidatorManager) HasQuorumFromMessages(msgs []*proto.Message) bool {
+	for _, message := range msgs {
+		if err := vm.SubmitMessage(message); err != nil {
+			vm.log.Error("HasQuorumFromMessages - failed to submit message for evidence tracking")
+		}
+	}
+
+	return vm.HasQuorum(convertMessageToAddressSet(msgs))
+}
+
 // HasPrepareQuorum provides information on whether prepared messages have reached the quorum
 func (vm *ValidatorManager) HasPrepareQuorum(stateName stateType, proposalMessage *proto.Message,
 	msgs []*proto.Message) bool {
@@ -125,6 +360,10 @@ func (vm *ValidatorManager) HasPrepareQuorum(stateName stateType, proposalMessag
 		string(proposerAddress): {},
 	}
 
+	if err := vm.SubmitMessage(proposalMessage); err != nil {
+		vm.log.Error("HasPrepareQuorum - failed to submit proposal message for evidence tracking")
+	}
+
 	for _, message := range msgs {
 		if bytes.Equal(message.From, proposerAddress) {
 			vm.log.Error("HasPrepareQuorum - proposer is among signers but it is not expected to be")
@@ -132,13 +371,18 @@ func (vm *ValidatorManager) HasPrepareQuorum(stateName stateType, proposalMessag
 			return false
 		}
 
+		if err := vm.SubmitMessage(message); err != nil {
+			vm.log.Error("HasPrepareQuorum - failed to submit message for evidence tracking")
+		}
+
 		sendersAddressesMap[string(message.From)] = struct{}{}
 	}
 
 	return vm.HasQuorum(sendersAddressesMap)
 }
 
-// calculateQuorum calculates the quorum size, which is FLOOR(2 * totalVotingPower / 3) + 1
+// calculateQuorum calculates the quorum size, which is FLOOR(2 * totalVotingPower / 3) + 1.
+// It backs the FloorTwoThirdsPlusOne QuorumPolicy
 func calculateQuorum(totalVotingPower *big.Int) *big.Int {
 	quorum := new(big.Int).Mul(totalVotingPower, big.NewInt(2))
 
@@ -146,6 +390,21 @@ func calculateQuorum(totalVotingPower *big.Int) *big.Int {
 	return quorum.Div(quorum, big.NewInt(3)).Add(quorum, big.NewInt(1))
 }
 
+// sortedAddresses returns validatorsVotingPower's keys sorted by address bytes, giving a stable
+// index used to map AggregateQuorumProof bitmap positions to validator addresses
+func sortedAddresses(validatorsVotingPower map[string]*big.Int) []string {
+	addresses := make([]string, 0, len(validatorsVotingPower))
+	for addr := range validatorsVotingPower {
+		addresses = append(addresses, addr)
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i] < addresses[j]
+	})
+
+	return addresses
+}
+
 func calculateTotalVotingPower(validatorsVotingPower map[string]*big.Int) *big.Int {
 	totalVotingPower := big.NewInt(0)
 	for _, validatorVotingPower := range validatorsVotingPower {
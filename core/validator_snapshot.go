@@ -0,0 +1,80 @@
+package core
+
+import (
+	"container/list"
+	"math/big"
+	"sync"
+)
+
+// defaultValidatorSnapshotCacheSize bounds how many distinct epochs' worth of validator sets are
+// kept in memory at once
+const defaultValidatorSnapshotCacheSize = 8
+
+// ValidatorSnapshot is a cached validator set for a given epoch, along with the height at which
+// that set last changed. It lets Init skip re-fetching and rebuilding the full validator set on
+// every height when the backing validator set only changes on epoch boundaries
+type ValidatorSnapshot struct {
+	// Epoch identifies the epoch this snapshot belongs to
+	Epoch uint64
+
+	// ChangedAt is the height at which the validator set for Epoch was last changed. It is used
+	// to detect a stale cache entry, e.g. if an epoch number were ever reused with a different set
+	ChangedAt uint64
+
+	// ValidatorsVotingPower is the validator set for Epoch
+	ValidatorsVotingPower map[string]*big.Int
+}
+
+// validatorSnapshotCache is a small LRU cache of ValidatorSnapshot, keyed by epoch
+type validatorSnapshotCache struct {
+	lock     sync.Mutex
+	capacity int
+	entries  map[uint64]*list.Element
+	order    *list.List // front = most recently used
+}
+
+func newValidatorSnapshotCache(capacity int) *validatorSnapshotCache {
+	return &validatorSnapshotCache{
+		capacity: capacity,
+		entries:  make(map[uint64]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *validatorSnapshotCache) get(epoch uint64) (*ValidatorSnapshot, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[epoch]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return element.Value.(*ValidatorSnapshot), true
+}
+
+func (c *validatorSnapshotCache) put(snapshot *ValidatorSnapshot) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if element, ok := c.entries[snapshot.Epoch]; ok {
+		element.Value = snapshot
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[snapshot.Epoch] = c.order.PushFront(snapshot)
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*ValidatorSnapshot).Epoch)
+	}
+}
@@ -0,0 +1,198 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+)
+
+// epochBackend is a ValidatorBackend + ValidatorSetEpochProvider whose GetVotingPowers call count
+// can be observed, so tests can assert whether the snapshot cache was hit or missed
+type epochBackend struct {
+	minerAddress string
+	votingPowers map[string]*big.Int
+	epoch        uint64
+	changedAt    uint64
+
+	getVotingPowersCalls int
+}
+
+func (b *epochBackend) GetVotingPowers(_ uint64) (map[string]*big.Int, error) {
+	b.getVotingPowersCalls++
+
+	copied := make(map[string]*big.Int, len(b.votingPowers))
+	for addr, votingPower := range b.votingPowers {
+		copied[addr] = votingPower
+	}
+
+	return copied, nil
+}
+
+func (b *epochBackend) GetMinerAddress() (string, error) {
+	return b.minerAddress, nil
+}
+
+func (b *epochBackend) GetValidatorSetEpoch(_ uint64) (uint64, uint64, error) {
+	return b.epoch, b.changedAt, nil
+}
+
+// TestInit_ReusesSnapshotWithinEpoch checks that Init reuses the cached ValidatorSnapshot, instead
+// of calling GetVotingPowers again, as long as the epoch's changedAt height is unchanged
+func TestInit_ReusesSnapshotWithinEpoch(t *testing.T) {
+	backend := &epochBackend{
+		minerAddress: "validator-1",
+		votingPowers: map[string]*big.Int{"validator-1": big.NewInt(1)},
+		epoch:        1,
+		changedAt:    10,
+	}
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	for height := uint64(10); height < 13; height++ {
+		if err := vm.Init(height); err != nil {
+			t.Fatalf("unexpected error initializing height %d: %v", height, err)
+		}
+	}
+
+	if backend.getVotingPowersCalls != 1 {
+		t.Fatalf("expected a single GetVotingPowers call across the epoch, got %d", backend.getVotingPowersCalls)
+	}
+}
+
+// TestInit_RefetchesOnEpochChange checks that Init calls GetVotingPowers again once changedAt
+// advances, i.e. the cached snapshot is treated as stale rather than reused forever
+func TestInit_RefetchesOnEpochChange(t *testing.T) {
+	backend := &epochBackend{
+		minerAddress: "validator-1",
+		votingPowers: map[string]*big.Int{"validator-1": big.NewInt(1)},
+		epoch:        1,
+		changedAt:    10,
+	}
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	if err := vm.Init(10); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	backend.changedAt = 11
+	backend.votingPowers = map[string]*big.Int{"validator-1": big.NewInt(2)}
+
+	if err := vm.Init(11); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	if backend.getVotingPowersCalls != 2 {
+		t.Fatalf("expected GetVotingPowers to be called again after changedAt advanced, got %d calls",
+			backend.getVotingPowersCalls)
+	}
+}
+
+// TestApplyValidatorDiff_AddAndRemoveSameAddress checks that a validator reported in both added
+// and removed is treated as a voting power update, not a net removal
+func TestApplyValidatorDiff_AddAndRemoveSameAddress(t *testing.T) {
+	backend := NewInMemoryValidatorBackend("validator-1", map[string]*big.Int{
+		"validator-1": big.NewInt(1),
+	})
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	if err := vm.Init(1); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	err = vm.ApplyValidatorDiff(
+		map[string]*big.Int{"validator-1": big.NewInt(5)},
+		map[string]*big.Int{"validator-1": big.NewInt(1)},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error applying diff: %v", err)
+	}
+
+	if got := vm.validatorsVotingPower["validator-1"]; got.Cmp(big.NewInt(5)) != 0 {
+		t.Fatalf("expected validator-1's voting power to be updated to 5, got %s", got)
+	}
+
+	if vm.totalVotingPower.Cmp(big.NewInt(5)) != 0 {
+		t.Fatalf("expected total voting power 5, got %s", vm.totalVotingPower)
+	}
+}
+
+// TestApplyValidatorDiff_RemoveUnknownAddress checks that removing an address that isn't in the
+// current validator set is a no-op rather than corrupting the total voting power
+func TestApplyValidatorDiff_RemoveUnknownAddress(t *testing.T) {
+	backend := NewInMemoryValidatorBackend("validator-1", map[string]*big.Int{
+		"validator-1": big.NewInt(1),
+	})
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	if err := vm.Init(1); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	err = vm.ApplyValidatorDiff(nil, map[string]*big.Int{"unknown-validator": big.NewInt(100)})
+	if err != nil {
+		t.Fatalf("unexpected error applying diff: %v", err)
+	}
+
+	if vm.totalVotingPower.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("expected total voting power to be unaffected by removing an unknown address, got %s",
+			vm.totalVotingPower)
+	}
+
+	if _, ok := vm.validatorsVotingPower["validator-1"]; !ok {
+		t.Fatal("expected validator-1 to remain in the validator set")
+	}
+}
+
+// TestApplyValidatorDiff_KeepsSnapshotCacheInSync checks that the epoch snapshot cache is updated
+// by ApplyValidatorDiff, so a subsequent Init for the same (epoch, changedAt) observes the diffed
+// set rather than reverting to the stale pre-diff snapshot
+func TestApplyValidatorDiff_KeepsSnapshotCacheInSync(t *testing.T) {
+	backend := &epochBackend{
+		minerAddress: "validator-1",
+		votingPowers: map[string]*big.Int{"validator-1": big.NewInt(1)},
+		epoch:        1,
+		changedAt:    10,
+	}
+
+	vm, err := NewValidatorManager(backend, noopLogger{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error creating validator manager: %v", err)
+	}
+
+	if err := vm.Init(10); err != nil {
+		t.Fatalf("unexpected error initializing: %v", err)
+	}
+
+	if err := vm.ApplyValidatorDiff(map[string]*big.Int{"validator-2": big.NewInt(4)}, nil); err != nil {
+		t.Fatalf("unexpected error applying diff: %v", err)
+	}
+
+	// Re-init for the same (epoch, changedAt): if the cache wasn't kept in sync, this would hit the
+	// stale pre-diff snapshot and silently revert the diff
+	if err := vm.Init(10); err != nil {
+		t.Fatalf("unexpected error re-initializing: %v", err)
+	}
+
+	if backend.getVotingPowersCalls != 1 {
+		t.Fatalf("expected the re-init to reuse the cache rather than refetch, got %d GetVotingPowers calls",
+			backend.getVotingPowersCalls)
+	}
+
+	if _, ok := vm.validatorsVotingPower["validator-2"]; !ok {
+		t.Fatal("expected validator-2 from the diff to survive the re-init")
+	}
+}